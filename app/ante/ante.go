@@ -0,0 +1,61 @@
+// Package ante implements the transaction validation pipeline used by
+// EthermintApplication's CheckTx and DeliverTx paths. Validation is split
+// into small, independently testable decorators chained together by
+// NewAnteHandler, mirroring the Cosmos SDK's AnteHandler/AnteDecorator
+// pattern. Forks can assemble their own chain - e.g. to insert fee grant
+// or allowlist checks - without touching the core decorators below.
+package ante
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+)
+
+// AnteHandler validates tx against the state carried in ctx and returns the
+// ABCI response to hand back from CheckTx/DeliverTx.
+type AnteHandler func(ctx Context, tx *ethTypes.Transaction) abciTypes.ResponseCheckTx
+
+// AnteDecorator performs one step of validation. It calls next to continue
+// the chain, giving it the chance to run logic both before and after the
+// rest of the pipeline - e.g. NonceIncrementDecorator only advances the
+// nonce once every decorator after it has also succeeded.
+type AnteDecorator interface {
+	AnteHandle(ctx Context, tx *ethTypes.Transaction, next AnteHandler) abciTypes.ResponseCheckTx
+}
+
+// NewAnteHandler assembles decorators into a single AnteHandler, invoked in
+// the order given. The last decorator's next is a no-op handler that
+// returns CodeTypeOK.
+func NewAnteHandler(decorators ...AnteDecorator) AnteHandler {
+	if len(decorators) == 0 {
+		return terminalAnteHandler
+	}
+	return chainAnteDecorators(decorators[0], decorators[1:])
+}
+
+func chainAnteDecorators(decorator AnteDecorator, rest []AnteDecorator) AnteHandler {
+	return func(ctx Context, tx *ethTypes.Transaction) abciTypes.ResponseCheckTx {
+		next := terminalAnteHandler
+		if len(rest) > 0 {
+			next = chainAnteDecorators(rest[0], rest[1:])
+		}
+		return decorator.AnteHandle(ctx, tx, next)
+	}
+}
+
+func terminalAnteHandler(ctx Context, tx *ethTypes.Transaction) abciTypes.ResponseCheckTx {
+	return abciTypes.ResponseCheckTx{Code: abciTypes.CodeTypeOK}
+}
+
+// RecoverSender recovers tx's sender using the signer appropriate for its
+// protection status. It's exposed for callers outside the ante chain (e.g.
+// event construction in DeliverTx) that need the same recovery logic
+// SignatureVerifyDecorator uses.
+func RecoverSender(tx *ethTypes.Transaction) (common.Address, error) {
+	var signer ethTypes.Signer = ethTypes.FrontierSigner{}
+	if tx.Protected() {
+		signer = ethTypes.NewEIP155Signer(tx.ChainId())
+	}
+	return ethTypes.Sender(signer, tx)
+}
@@ -0,0 +1,21 @@
+package ante
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+)
+
+// Context carries the per-transaction inputs decorators need: the
+// speculative state to read and mutate, the current block gas limit, and
+// the sender recovered by SignatureVerifyDecorator.
+type Context struct {
+	State    *state.StateDB
+	GasLimit uint64
+	Sender   common.Address
+}
+
+// WithSender returns a copy of ctx with Sender set to sender.
+func (ctx Context) WithSender(sender common.Address) Context {
+	ctx.Sender = sender
+	return ctx
+}
@@ -0,0 +1,159 @@
+package ante
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+
+	cosmosErrors "github.com/cosmos/cosmos-sdk/types"
+)
+
+// SizeLimitDecorator rejects transactions over MaxTxSize bytes to
+// prevent DOS attacks via oversized payloads.
+type SizeLimitDecorator struct {
+	MaxTxSize uint64
+}
+
+// AnteHandle implements AnteDecorator.
+func (d SizeLimitDecorator) AnteHandle(ctx Context, tx *ethTypes.Transaction, next AnteHandler) abciTypes.ResponseCheckTx {
+	if uint64(tx.Size()) > d.MaxTxSize {
+		return abciTypes.ResponseCheckTx{
+			Code: uint32(cosmosErrors.CodeInternal),
+			Log:  core.ErrOversizedData.Error(),
+		}
+	}
+	return next(ctx, tx)
+}
+
+// SignatureVerifyDecorator recovers and validates the transaction's sender,
+// rejects negative values, and makes sure the sender account exists before
+// handing the recovered sender on to the rest of the chain.
+type SignatureVerifyDecorator struct{}
+
+// AnteHandle implements AnteDecorator.
+func (d SignatureVerifyDecorator) AnteHandle(ctx Context, tx *ethTypes.Transaction, next AnteHandler) abciTypes.ResponseCheckTx {
+	from, err := RecoverSender(tx)
+	if err != nil {
+		// TODO: Add cosmosErrors.CodeTypeInvalidSignature ?
+		return abciTypes.ResponseCheckTx{
+			Code: uint32(cosmosErrors.CodeInternal),
+			Log:  core.ErrInvalidSender.Error(),
+		}
+	}
+
+	// Transactions can't be negative. This may never happen using RLP
+	// decoded transactions but may occur if you create a transaction
+	// using the RPC.
+	if tx.Value().Sign() < 0 {
+		return abciTypes.ResponseCheckTx{
+			Code: uint32(cosmosErrors.CodeUnknownRequest),
+			Log:  core.ErrNegativeValue.Error(),
+		}
+	}
+
+	// Make sure the account exists - can't send from a non-existing account.
+	if !ctx.State.Exist(from) {
+		return abciTypes.ResponseCheckTx{
+			Code: uint32(cosmosErrors.CodeUnknownAddress),
+			Log:  core.ErrInvalidSender.Error(),
+		}
+	}
+
+	return next(ctx.WithSender(from), tx)
+}
+
+// NonceIncrementDecorator checks that the transaction's nonce matches the
+// sender's current nonce, then - only once the rest of the chain has also
+// succeeded - advances it. Keeping the increment as post-processing means a
+// later failure (e.g. insufficient balance) never leaves the nonce bumped.
+type NonceIncrementDecorator struct{}
+
+// AnteHandle implements AnteDecorator.
+func (d NonceIncrementDecorator) AnteHandle(ctx Context, tx *ethTypes.Transaction, next AnteHandler) abciTypes.ResponseCheckTx {
+	nonce := ctx.State.GetNonce(ctx.Sender)
+	if nonce != tx.Nonce() {
+		return abciTypes.ResponseCheckTx{
+			Code: uint32(cosmosErrors.CodeInternal),
+			Log: fmt.Sprintf(
+				"Nonce not strictly increasing. Expected %d Got %d",
+				nonce, tx.Nonce()),
+		}
+	}
+
+	res := next(ctx, tx)
+	if res.IsErr() {
+		return res
+	}
+
+	ctx.State.SetNonce(ctx.Sender, tx.Nonce()+1)
+	return res
+}
+
+// GasCheckDecorator rejects transactions whose gas exceeds the current
+// block's gas limit.
+type GasCheckDecorator struct{}
+
+// AnteHandle implements AnteDecorator.
+func (d GasCheckDecorator) AnteHandle(ctx Context, tx *ethTypes.Transaction, next AnteHandler) abciTypes.ResponseCheckTx {
+	if ctx.GasLimit < tx.Gas() {
+		return abciTypes.ResponseCheckTx{
+			Code: uint32(cosmosErrors.CodeOutOfGas),
+			Log:  core.ErrGasLimitReached.Error(),
+		}
+	}
+	return next(ctx, tx)
+}
+
+// IntrinsicGasDecorator rejects transactions that don't carry enough gas to
+// cover the intrinsic cost of their payload.
+type IntrinsicGasDecorator struct{}
+
+// AnteHandle implements AnteDecorator.
+func (d IntrinsicGasDecorator) AnteHandle(ctx Context, tx *ethTypes.Transaction, next AnteHandler) abciTypes.ResponseCheckTx {
+	intrGas, err := core.IntrinsicGas(tx.Data(), tx.To() == nil, true) // homestead == true
+	if err != nil {
+		panic("Intrinsic gas failed!")
+	}
+	if tx.Gas() < intrGas {
+		return abciTypes.ResponseCheckTx{
+			Code: uint32(cosmosErrors.CodeUnknownRequest),
+			Log:  core.ErrIntrinsicGas.Error(),
+		}
+	}
+	return next(ctx, tx)
+}
+
+// BalanceDeductDecorator checks the sender can cover the transaction's cost
+// (value + gasPrice*gasLimit) and, once the rest of the chain succeeds,
+// moves the balance between sender and recipient.
+type BalanceDeductDecorator struct{}
+
+// AnteHandle implements AnteDecorator.
+func (d BalanceDeductDecorator) AnteHandle(ctx Context, tx *ethTypes.Transaction, next AnteHandler) abciTypes.ResponseCheckTx {
+	currentBalance := ctx.State.GetBalance(ctx.Sender)
+	if currentBalance.Cmp(tx.Cost()) < 0 {
+		return abciTypes.ResponseCheckTx{
+			// TODO: Add cosmosErrors.CodeTypeInsufficientFunds ?
+			Code: uint32(cosmosErrors.CodeUnknownRequest),
+			Log: fmt.Sprintf(
+				"Current balance: %s, tx cost: %s",
+				currentBalance, tx.Cost()),
+		}
+	}
+
+	res := next(ctx, tx)
+	if res.IsErr() {
+		return res
+	}
+
+	// amount + gasprice * gaslimit
+	ctx.State.SubBalance(ctx.Sender, tx.Cost())
+	// tx.To() returns a pointer to a common address. It returns nil
+	// if it is a contract creation transaction.
+	if to := tx.To(); to != nil {
+		ctx.State.AddBalance(*to, tx.Value())
+	}
+	return res
+}
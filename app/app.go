@@ -8,15 +8,18 @@ import (
 	"math/big"
 	"path/filepath"
 
-	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rpc"
 	cli "gopkg.in/urfave/cli.v1"
 
+	"github.com/ya-enot/etherus/app/ante"
 	"github.com/ya-enot/etherus/ethereum"
 	"github.com/ya-enot/etherus/ethereum/validators"
+	"github.com/ya-enot/etherus/locker"
+	"github.com/ya-enot/etherus/private"
+	"github.com/ya-enot/etherus/rpcapi"
 	emtTypes "github.com/ya-enot/etherus/types"
 
 	cosmosErrors "github.com/cosmos/cosmos-sdk/types"
@@ -57,6 +60,54 @@ type EthermintApplication struct {
 	requestBeginBlock *abciTypes.RequestBeginBlock
 
 	appDb *ethdb.LDBDatabase
+
+	// anteHandler is the decorator chain CheckTx runs every transaction
+	// through. Assembled once in NewEthermintApplication so forks can
+	// override the decorator set without touching CheckTx itself.
+	anteHandler ante.AnteHandler
+
+	// blockValidator validates the committed block's header and state root
+	// in Commit. It defaults to the backend's own blockchain.Validator(),
+	// but can be swapped (tracing, private state) via SetBlockValidator.
+	//
+	// stateProcessor mirrors the backend's own blockchain.Processor() and
+	// is available to forks via SetStateProcessor for an independent
+	// re-execution pass (tracing, parallel EVM); Commit itself no longer
+	// calls it, since receipts/usedGas for ValidateState now come from
+	// pendingReceipts - the per-tx receipts DeliverTx already produced -
+	// rather than replaying the whole block a second time.
+	blockValidator BlockValidator
+	stateProcessor StateProcessor
+
+	// pendingReceipts accumulates the receipts DeliverTx produces for the
+	// block currently being assembled. BeginBlock resets it, Commit
+	// consumes it to validate the committed state without re-executing
+	// the block.
+	pendingReceipts []*ethTypes.Receipt
+
+	// privateState and privateTxManager support Quorum-style private
+	// transactions. Both are nil unless a transaction manager is
+	// configured via private.ConfigFlag/PRIVATE_CONFIG, in which case
+	// DeliverTx executes transactions private.IsPrivate marks against
+	// privateState instead of the public state.
+	// chainID is this node's configured chain ID, needed to tell a
+	// private.IsPrivate marker apart from an ordinary EIP-155 signature
+	// that happens to collide with it.
+	chainID          *big.Int
+	privateState     *state.StateDB
+	privateTxManager *private.Manager
+
+	// rpcACL gates which JSON-RPC namespaces Query may dispatch to, and
+	// debugAPI serves the debug namespace locally instead of forwarding
+	// it through rpcClient.
+	rpcACL   *rpcapi.ACL
+	debugAPI *rpcapi.DebugAPI
+
+	// addrLocker closes the CheckTx/DeliverTx race on checkTxState: it
+	// serializes the read-modify-write section of validateTx per sender,
+	// and fences the checkTxState swap in Commit against new CheckTx
+	// calls.
+	addrLocker *locker.AddrLocker
 }
 
 // NewEthermintApplication creates a fully initialised instance of EthermintApplication
@@ -95,6 +146,37 @@ func NewEthermintApplication(ctx *cli.Context, backend *ethereum.Backend,
 		appDb:       appDb,
 	}
 
+	app.anteHandler = NewDefaultAnteHandler()
+	app.addrLocker = locker.NewAddrLocker()
+
+	blockchain := backend.Ethereum().BlockChain()
+	app.blockValidator = defaultBlockValidator(blockchain)
+	app.stateProcessor = defaultStateProcessor(blockchain)
+	app.chainID = blockchain.Config().ChainId
+
+	app.rpcACL = rpcapi.ACLFromContext(ctx)
+	app.debugAPI = rpcapi.NewDebugAPI(backend)
+
+	if cfg, ok := private.ConfigFromContext(ctx); ok {
+		manager, err := private.NewManager(cfg)
+		if err != nil {
+			return nil, err
+		}
+		app.privateTxManager = manager
+		app.privateState = state.Copy()
+
+		// Register eth_sendPrivateTransaction/eth_getPrivateTransaction
+		// under the "eth" namespace so they're reachable the same way as
+		// any other geth RPC method - without this, private.API is just
+		// unused code no client can call.
+		app.backend.RegisterAPI(rpc.API{
+			Namespace: string(rpcapi.NamespaceEth),
+			Version:   "1.0",
+			Service:   private.NewAPI(manager),
+			Public:    true,
+		})
+	}
+
 	if err := app.backend.InitEthState(common.Address{}); err != nil {
 		return nil, err
 	}
@@ -102,12 +184,43 @@ func NewEthermintApplication(ctx *cli.Context, backend *ethereum.Backend,
 	return app, nil
 }
 
+// NewDefaultAnteHandler assembles the standard validation pipeline used by
+// CheckTx: size limit, signature recovery, nonce check, gas checks, and
+// balance deduction. Forks that need to insert their own decorators (fee
+// grants, allowlists, EIP-2930 access lists) should build their own chain
+// with ante.NewAnteHandler instead of editing this one.
+func NewDefaultAnteHandler() ante.AnteHandler {
+	return ante.NewAnteHandler(
+		ante.SizeLimitDecorator{MaxTxSize: maxTransactionSize},
+		ante.SignatureVerifyDecorator{},
+		ante.NonceIncrementDecorator{},
+		ante.GasCheckDecorator{},
+		ante.IntrinsicGasDecorator{},
+		ante.BalanceDeductDecorator{},
+	)
+}
+
 // SetLogger sets the logger for the ethermint application
 // #unstable
 func (app *EthermintApplication) SetLogger(log tmLog.Logger) {
 	app.logger = log
 }
 
+// SetBlockValidator overrides the BlockValidator used in Commit, letting
+// forks plug in e.g. a private-state-aware validator.
+// #unstable
+func (app *EthermintApplication) SetBlockValidator(validator BlockValidator) {
+	app.blockValidator = validator
+}
+
+// SetStateProcessor overrides the StateProcessor stored on the app, letting
+// forks plug in e.g. a tracing or parallel EVM processor for their own
+// re-execution pass. The app itself does not call it.
+// #unstable
+func (app *EthermintApplication) SetStateProcessor(processor StateProcessor) {
+	app.stateProcessor = processor
+}
+
 var bigZero = big.NewInt(0)
 
 // maxTransactionSize is 32KB in order to prevent DOS attacks
@@ -138,10 +251,24 @@ func (app *EthermintApplication) Info(req abciTypes.RequestInfo) abciTypes.Respo
 	return abciTypes.ResponseInfo{
 		Data:             "ABCIEthereum",
 		LastBlockHeight:  height.Int64(),
-		LastBlockAppHash: hash[:],
+		LastBlockAppHash: app.appHash(hash),
 	}
 }
 
+// appHash returns the app hash tendermint should record for publicRoot, the
+// public chain's block hash that Info and Commit report:
+// publicRoot alone, or publicRoot with the current private state root
+// appended when this node has a private-tx manager configured, so callers
+// can observe the private root alongside the public one instead of it only
+// ever reaching a log line.
+func (app *EthermintApplication) appHash(publicRoot common.Hash) []byte {
+	if app.privateState == nil {
+		return publicRoot[:]
+	}
+	privateRoot := app.PrivateStateRoot()
+	return append(append([]byte{}, publicRoot[:]...), privateRoot[:]...)
+}
+
 // SetOption sets a configuration option
 // #stable - 0.4.0
 func (app *EthermintApplication) SetOption(req abciTypes.RequestSetOption) abciTypes.ResponseSetOption {
@@ -190,7 +317,14 @@ func (app *EthermintApplication) DeliverTx(txBytes []byte) abciTypes.ResponseDel
 	}
 	app.logger.Debug("DeliverTx: Received valid transaction", "tx", tx) // nolint: errcheck
 
-	res := app.backend.DeliverTx(tx)
+	if app.privateTxManager != nil && private.IsPrivate(tx, app.chainID) {
+		res, receipt := app.deliverPrivateTx(tx)
+		app.pendingReceipts = append(app.pendingReceipts, receipt)
+		return res
+	}
+
+	res, receipt := app.backend.DeliverTx(tx)
+	app.pendingReceipts = append(app.pendingReceipts, receipt)
 	if res.IsErr() {
 		// nolint: errcheck
 		app.logger.Error("DeliverTx: Error delivering tx to ethereum backend", "tx", tx,
@@ -199,8 +333,22 @@ func (app *EthermintApplication) DeliverTx(txBytes []byte) abciTypes.ResponseDel
 	}
 	app.CollectTx(tx)
 
+	from, err := ante.RecoverSender(tx)
+	if err != nil {
+		// nolint: errcheck
+		app.logger.Error("DeliverTx: recovering sender for events failed", "tx", tx.Hash().Hex(), "err", err)
+	}
+
+	var gasUsed uint64
+	var logs []*ethTypes.Log
+	if receipt != nil {
+		gasUsed = receipt.GasUsed
+		logs = receipt.Logs
+	}
+
 	return abciTypes.ResponseDeliverTx{
-		Code: abciTypes.CodeTypeOK,
+		Code:   abciTypes.CodeTypeOK,
+		Events: deliverTxEvents(tx, from, gasUsed, logs),
 	}
 }
 
@@ -211,6 +359,7 @@ func (app *EthermintApplication) BeginBlock(beginBlock abciTypes.RequestBeginBlo
 	app.logger.Debug("BeginBlock") // nolint: errcheck
 
 	app.requestBeginBlock = &beginBlock
+	app.pendingReceipts = nil
 
 	validatorAddress := common.BytesToAddress(beginBlock.Header.Proposer.Address)
 	app.logger.Debug("Proposer address is ", "validatorAddress", validatorAddress)
@@ -228,8 +377,14 @@ func (app *EthermintApplication) BeginBlock(beginBlock abciTypes.RequestBeginBlo
 // #stable - 0.4.0
 func (app *EthermintApplication) EndBlock(endBlock abciTypes.RequestEndBlock) abciTypes.ResponseEndBlock {
 	app.logger.Debug("EndBlock", "height", endBlock.GetHeight()) // nolint: errcheck
-	app.backend.AccumulateRewards(app.strategy)
-	return app.GetUpdatedValidators()
+
+	reward, err := app.backend.AccumulateRewards(app.strategy)
+
+	res := app.GetUpdatedValidators()
+	if err == nil && reward != nil && app.myValidator != nil {
+		res.Events = append(res.Events, rewardEvents(*app.myValidator, reward)...)
+	}
+	return res
 }
 
 // Commit commits the block and returns a hash of the current state
@@ -251,12 +406,46 @@ func (app *EthermintApplication) Commit() abciTypes.ResponseCommit {
 		panic(errors.New("Error getting latest state"))
 	}
 
+	// Validate the committed block's header and state root against the
+	// receipts DeliverTx already produced for it (pendingReceipts), rather
+	// than re-deriving them by replaying the whole block a second time
+	// through stateProcessor: that would double per-block EVM execution
+	// cost, and - being an independent re-derivation rather than a read of
+	// what was actually applied - risks panicking the node on spurious
+	// divergence. By the time Commit observes it the block is already
+	// committed, so a validation failure here is logged rather than fatal.
+	currentBlock := app.backend.Ethereum().BlockChain().CurrentBlock()
+	if err := app.blockValidator.ValidateBlock(currentBlock); err != nil {
+		// nolint: errcheck
+		app.logger.Error("Error validating block header", "err", err)
+	} else if err := app.blockValidator.ValidateState(currentBlock, state, ethTypes.Receipts(app.pendingReceipts), usedGasOf(app.pendingReceipts)); err != nil {
+		// nolint: errcheck
+		app.logger.Error("Error validating committed state", "err", err)
+	}
+
+	app.addrLocker.Lock()
 	app.checkTxState = state.Copy()
+	app.addrLocker.Unlock()
 	app.requestBeginBlock = nil
+	app.pendingReceipts = nil
+
+	if app.privateState != nil {
+		// nolint: errcheck
+		app.logger.Info("Commiting private state", "privateRoot", hex.EncodeToString(app.PrivateStateRoot().Bytes()))
+	}
 
 	return abciTypes.ResponseCommit{
-		Data: blockHash[:],
+		Data: app.appHash(blockHash),
+	}
+}
+
+// PrivateStateRoot returns the current root of the private state tree, or
+// the zero hash if no transaction manager is configured for this node.
+func (app *EthermintApplication) PrivateStateRoot() common.Hash {
+	if app.privateState == nil {
+		return common.Hash{}
 	}
+	return app.privateState.IntermediateRoot(true)
 }
 
 // Query queries the state of the EthermintApplication
@@ -268,8 +457,21 @@ func (app *EthermintApplication) Query(query abciTypes.RequestQuery) abciTypes.R
 		return abciTypes.ResponseQuery{Code: uint32(cosmosErrors.CodeInternal),
 			Log: err.Error()}
 	}
+	if !app.rpcACL.Allows(in.Method) {
+		return abciTypes.ResponseQuery{
+			Code: uint32(cosmosErrors.CodeUnauthorized),
+			Log:  fmt.Sprintf("namespace %q is not enabled for ABCI queries", rpcapi.NamespaceOf(in.Method)),
+		}
+	}
+
 	var result interface{}
-	if err := app.rpcClient.Call(&result, in.Method, in.Params...); err != nil {
+	var err error
+	if rpcapi.NamespaceOf(in.Method) == rpcapi.NamespaceDebug {
+		result, err = app.debugAPI.Dispatch(in.Method, in.Params)
+	} else {
+		err = app.rpcClient.Call(&result, in.Method, in.Params...)
+	}
+	if err != nil {
 		return abciTypes.ResponseQuery{Code: uint32(cosmosErrors.CodeInternal),
 			Log: err.Error()}
 	}
@@ -283,98 +485,28 @@ func (app *EthermintApplication) Query(query abciTypes.RequestQuery) abciTypes.R
 
 //-------------------------------------------------------
 
-// validateTx checks the validity of a tx against the blockchain's current state.
-// it duplicates the logic in ethereum's tx_pool
+// validateTx checks the validity of a tx against the blockchain's current
+// state by running it through app.anteHandler. It duplicates the logic in
+// ethereum's tx_pool.
 func (app *EthermintApplication) validateTx(tx *ethTypes.Transaction) abciTypes.ResponseCheckTx {
-
-	// Heuristic limit, reject transactions over 32KB to prevent DOS attacks
-	if tx.Size() > maxTransactionSize {
-		return abciTypes.ResponseCheckTx{
-			Code: uint32(cosmosErrors.CodeInternal),
-			Log:  core.ErrOversizedData.Error()}
-	}
-
-	var signer ethTypes.Signer = ethTypes.FrontierSigner{}
-	if tx.Protected() {
-		signer = ethTypes.NewEIP155Signer(tx.ChainId())
-	}
-
-	// Make sure the transaction is signed properly
-	from, err := ethTypes.Sender(signer, tx)
-	if err != nil {
-		// TODO: Add cosmosErrors.CodeTypeInvalidSignature ?
-		return abciTypes.ResponseCheckTx{
-			Code: uint32(cosmosErrors.CodeInternal),
-			Log:  core.ErrInvalidSender.Error()}
+	// RLock fences our read of app.checkTxState against Commit's Lock'd
+	// swap of that same field - held for the whole call, not just the
+	// per-address section below, since the race is on the field itself.
+	app.addrLocker.RLock()
+	defer app.addrLocker.RUnlock()
+
+	// LockAddr additionally serializes the read-modify-write section for
+	// a given sender across concurrent CheckTx calls: otherwise two
+	// CheckTx calls for the same sender can both read the pre-tx
+	// nonce/balance before either writes its update.
+	if from, err := ante.RecoverSender(tx); err == nil {
+		app.addrLocker.LockAddr(from)
+		defer app.addrLocker.UnlockAddr(from)
 	}
 
-	// Transactions can't be negative. This may never happen using RLP decoded
-	// transactions but may occur if you create a transaction using the RPC.
-	if tx.Value().Sign() < 0 {
-		return abciTypes.ResponseCheckTx{
-			Code: uint32(cosmosErrors.CodeUnknownRequest),
-			Log:  core.ErrNegativeValue.Error()}
+	ctx := ante.Context{
+		State:    app.checkTxState,
+		GasLimit: app.backend.GasLimit(),
 	}
-
-	currentState := app.checkTxState
-
-	// Make sure the account exist - cant send from non-existing account.
-	if !currentState.Exist(from) {
-		return abciTypes.ResponseCheckTx{
-			Code: uint32(cosmosErrors.CodeUnknownAddress),
-			Log:  core.ErrInvalidSender.Error()}
-	}
-
-	// Check the transaction doesn't exceed the current block limit gas.
-	gasLimit := app.backend.GasLimit()
-	if gasLimit < tx.Gas() {
-		return abciTypes.ResponseCheckTx{
-			Code: uint32(cosmosErrors.CodeOutOfGas),
-			Log:  core.ErrGasLimitReached.Error()}
-	}
-
-	// Check if nonce is not strictly increasing
-	nonce := currentState.GetNonce(from)
-	if nonce != tx.Nonce() {
-		return abciTypes.ResponseCheckTx{
-			Code: uint32(cosmosErrors.CodeInternal),
-			Log: fmt.Sprintf(
-				"Nonce not strictly increasing. Expected %d Got %d",
-				nonce, tx.Nonce())}
-	}
-
-	// Transactor should have enough funds to cover the costs
-	// cost == V + GP * GL
-	currentBalance := currentState.GetBalance(from)
-	if currentBalance.Cmp(tx.Cost()) < 0 {
-		return abciTypes.ResponseCheckTx{
-			// TODO: Add cosmosErrors.CodeTypeInsufficientFunds ?
-			Code: uint32(cosmosErrors.CodeUnknownRequest),
-			Log: fmt.Sprintf(
-				"Current balance: %s, tx cost: %s",
-				currentBalance, tx.Cost())}
-	}
-
-	intrGas, err := core.IntrinsicGas(tx.Data(), tx.To() == nil, true) // homestead == true
-	if err != nil {
-		app.logger.Error("Intrinsic gas failed: ", "err", err)
-		panic("Intrinsic gas failed!")
-	}
-	if tx.Gas() < intrGas {
-		return abciTypes.ResponseCheckTx{
-			Code: uint32(cosmosErrors.CodeUnknownRequest),
-			Log:  core.ErrIntrinsicGas.Error()}
-	}
-
-	// Update ether balances
-	// amount + gasprice * gaslimit
-	currentState.SubBalance(from, tx.Cost())
-	// tx.To() returns a pointer to a common address. It returns nil
-	// if it is a contract creation transaction.
-	if to := tx.To(); to != nil {
-		currentState.AddBalance(*to, tx.Value())
-	}
-	currentState.SetNonce(from, tx.Nonce()+1)
-
-	return abciTypes.ResponseCheckTx{Code: abciTypes.CodeTypeOK}
+	return app.anteHandler(ctx, tx)
 }
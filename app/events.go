@@ -0,0 +1,104 @@
+package app
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+)
+
+// Event types and attribute keys. These mirror the Cosmos SDK's own event
+// conventions - a typed event per concern plus a generic "message" event
+// carrying the module/sender pair - so Tendermint's tx indexer can filter
+// on queries like "ethereum_tx.recipient=0x...".
+const (
+	EventTypeEthereumTx    = "ethereum_tx"
+	EventTypeEthereumTxLog = "ethereum_tx.log"
+	EventTypeMessage       = "message"
+	EventTypeReward        = "reward"
+
+	AttributeKeyModule    = "module"
+	AttributeKeySender    = "sender"
+	AttributeKeyRecipient = "recipient"
+	AttributeKeyAmount    = "amount"
+	AttributeKeyGasUsed   = "gas_used"
+	AttributeKeyNonce     = "nonce"
+	AttributeKeyTxHash    = "hash"
+	AttributeKeyAddress   = "address"
+	AttributeKeyData      = "data"
+	AttributeKeyValidator = "validator"
+
+	ModuleNameEVM = "evm"
+)
+
+// deliverTxEvents builds the ABCI events DeliverTx attaches to a
+// successfully executed transaction: one ethereum_tx event, one
+// ethereum_tx.log event per emitted log, and a generic message event.
+func deliverTxEvents(tx *ethTypes.Transaction, from common.Address, gasUsed uint64, logs []*ethTypes.Log) []abciTypes.Event {
+	events := make([]abciTypes.Event, 0, len(logs)+2)
+	events = append(events, newEthereumTxEvent(tx, from, gasUsed), newMessageEvent(from))
+	for _, log := range logs {
+		events = append(events, newEthereumTxLogEvent(log))
+	}
+	return events
+}
+
+func newEthereumTxEvent(tx *ethTypes.Transaction, from common.Address, gasUsed uint64) abciTypes.Event {
+	var recipient string
+	if to := tx.To(); to != nil {
+		recipient = to.Hex()
+	}
+	return abciTypes.Event{
+		Type: EventTypeEthereumTx,
+		Attributes: []abciTypes.EventAttribute{
+			attr(AttributeKeyTxHash, tx.Hash().Hex()),
+			attr(AttributeKeyNonce, fmt.Sprintf("%d", tx.Nonce())),
+			attr(AttributeKeySender, from.Hex()),
+			attr(AttributeKeyRecipient, recipient),
+			attr(AttributeKeyAmount, tx.Value().String()),
+			attr(AttributeKeyGasUsed, fmt.Sprintf("%d", gasUsed)),
+		},
+	}
+}
+
+func newEthereumTxLogEvent(log *ethTypes.Log) abciTypes.Event {
+	attrs := []abciTypes.EventAttribute{attr(AttributeKeyAddress, log.Address.Hex())}
+	for i, topic := range log.Topics {
+		if i > 3 {
+			break
+		}
+		attrs = append(attrs, attr(fmt.Sprintf("topic%d", i), topic.Hex()))
+	}
+	attrs = append(attrs, attr(AttributeKeyData, common.Bytes2Hex(log.Data)))
+	return abciTypes.Event{Type: EventTypeEthereumTxLog, Attributes: attrs}
+}
+
+func newMessageEvent(sender common.Address) abciTypes.Event {
+	return abciTypes.Event{
+		Type: EventTypeMessage,
+		Attributes: []abciTypes.EventAttribute{
+			attr(AttributeKeyModule, ModuleNameEVM),
+			attr(AttributeKeySender, sender.Hex()),
+		},
+	}
+}
+
+// rewardEvents builds the ABCI event EndBlock attaches when it accumulates
+// a block reward for validator.
+func rewardEvents(validator common.Address, reward *big.Int) []abciTypes.Event {
+	return []abciTypes.Event{
+		{
+			Type: EventTypeReward,
+			Attributes: []abciTypes.EventAttribute{
+				attr(AttributeKeyValidator, validator.Hex()),
+				attr(AttributeKeyAmount, reward.String()),
+			},
+		},
+	}
+}
+
+func attr(key, value string) abciTypes.EventAttribute {
+	return abciTypes.EventAttribute{Key: []byte(key), Value: []byte(value)}
+}
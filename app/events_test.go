@@ -0,0 +1,22 @@
+package app
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRewardEventsAmount(t *testing.T) {
+	validator := common.HexToAddress("0x1")
+	events := rewardEvents(validator, big.NewInt(5))
+
+	if len(events) != 1 {
+		t.Fatalf("expected one reward event, got %d", len(events))
+	}
+	for _, a := range events[0].Attributes {
+		if string(a.Key) == AttributeKeyAmount && string(a.Value) != "5" {
+			t.Errorf("amount = %q, want %q", a.Value, "5")
+		}
+	}
+}
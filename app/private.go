@@ -0,0 +1,50 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+
+	cosmosErrors "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ya-enot/etherus/private"
+)
+
+// deliverPrivateTx executes a transaction private.IsPrivate has flagged as
+// private. If this node is a participant - the transaction manager returns
+// a non-nil payload for the hash tx carries as its calldata - it replays the
+// real payload against privateState, whose root Commit and Info report
+// alongside the public one (see appHash). Either way, the
+// sender's nonce on the node's real committing state (the same state
+// app.backend.DeliverTx mutates for public transactions) must advance, so
+// that's delegated to app.backend.DeliverPrivateTx rather than faked here
+// against a throwaway state snapshot. It returns a receipt alongside the
+// response, same as app.backend.DeliverTx, so Commit can validate the
+// block's committed state against the receipts DeliverTx actually produced.
+func (app *EthermintApplication) deliverPrivateTx(tx *ethTypes.Transaction) (abciTypes.ResponseDeliverTx, *ethTypes.Receipt) {
+	from, err := private.RecoverSender(tx)
+	if err != nil {
+		return abciTypes.ResponseDeliverTx{
+			Code: uint32(cosmosErrors.CodeInternal),
+			Log:  err.Error(),
+		}, nil
+	}
+
+	payload, err := app.privateTxManager.Receive(common.BytesToHash(tx.Data()).Hex())
+	if err != nil {
+		// nolint: errcheck
+		app.logger.Error("DeliverTx: fetching private payload failed", "tx", tx.Hash().Hex(), "err", err)
+		payload = nil
+	}
+
+	// DeliverPrivateTx always advances from's nonce, whether or not
+	// payload is nil: participants (payload != nil) additionally get it
+	// executed against privateState, while non-participants get only the
+	// nonce bump - but every validator agrees on that much regardless of
+	// who can see the payload.
+	res, receipt := app.backend.DeliverPrivateTx(tx, from, payload, app.privateState)
+	if res.IsErr() {
+		// nolint: errcheck
+		app.logger.Error("DeliverTx: delivering private tx failed", "tx", tx.Hash().Hex(), "err", res.Log)
+	}
+	return res, receipt
+}
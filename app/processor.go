@@ -0,0 +1,74 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// BlockValidator checks a block's header and, given the receipts its
+// transactions produced, its resulting state root and gas usage. It mirrors go-ethereum's
+// core.Validator so alternative implementations (tracing processors,
+// parallel EVM, private-state processors) can be plugged into
+// EthermintApplication without forking it.
+type BlockValidator interface {
+	ValidateBlock(block *ethTypes.Block) error
+	ValidateState(block *ethTypes.Block, statedb *state.StateDB, receipts ethTypes.Receipts, usedGas uint64) error
+}
+
+// StateProcessor applies a block's transactions to statedb and returns the
+// resulting receipts, logs, and gas used. It mirrors go-ethereum's
+// core.Processor.
+type StateProcessor interface {
+	Process(block *ethTypes.Block, statedb *state.StateDB) (ethTypes.Receipts, []*ethTypes.Log, uint64, error)
+}
+
+// chainBlockValidator adapts a *core.BlockChain's own Validator - the
+// behavior EthermintApplication always used - to BlockValidator.
+type chainBlockValidator struct {
+	validator core.Validator
+}
+
+func (v chainBlockValidator) ValidateBlock(block *ethTypes.Block) error {
+	return v.validator.ValidateBody(block)
+}
+
+func (v chainBlockValidator) ValidateState(block *ethTypes.Block, statedb *state.StateDB, receipts ethTypes.Receipts, usedGas uint64) error {
+	return v.validator.ValidateState(block, statedb, receipts, usedGas)
+}
+
+// chainStateProcessor adapts a *core.BlockChain's own Processor to
+// StateProcessor, running with the default VM config.
+type chainStateProcessor struct {
+	processor core.Processor
+}
+
+func (p chainStateProcessor) Process(block *ethTypes.Block, statedb *state.StateDB) (ethTypes.Receipts, []*ethTypes.Log, uint64, error) {
+	return p.processor.Process(block, statedb, vm.Config{})
+}
+
+// defaultBlockValidator and defaultStateProcessor wrap blockchain's own
+// Validator/Processor, preserving the behavior EthermintApplication had
+// before these were made overridable.
+func defaultBlockValidator(blockchain *core.BlockChain) BlockValidator {
+	return chainBlockValidator{validator: blockchain.Validator()}
+}
+
+func defaultStateProcessor(blockchain *core.BlockChain) StateProcessor {
+	return chainStateProcessor{processor: blockchain.Processor()}
+}
+
+// usedGasOf sums the gas used across receipts - the total ValidateState
+// checks against the block header's GasUsed. A nil entry (a tx whose
+// delivery errored before producing a receipt) contributes zero rather
+// than panicking on a nil dereference.
+func usedGasOf(receipts ethTypes.Receipts) uint64 {
+	var total uint64
+	for _, r := range receipts {
+		if r != nil {
+			total += r.GasUsed
+		}
+	}
+	return total
+}
@@ -0,0 +1,23 @@
+package app
+
+import (
+	"testing"
+
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestUsedGasOf(t *testing.T) {
+	receipts := ethTypes.Receipts{
+		{GasUsed: 21000},
+		nil, // a tx whose delivery errored before producing a receipt
+		{GasUsed: 50000},
+	}
+
+	if got, want := usedGasOf(receipts), uint64(71000); got != want {
+		t.Errorf("usedGasOf = %d, want %d", got, want)
+	}
+
+	if got := usedGasOf(nil); got != 0 {
+		t.Errorf("usedGasOf(nil) = %d, want 0", got)
+	}
+}
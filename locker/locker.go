@@ -0,0 +1,79 @@
+// Package locker provides per-address locking for EthermintApplication's
+// speculative state. validateTx mutates app.checkTxState (SubBalance,
+// AddBalance, SetNonce) with no synchronization of its own, so concurrent
+// CheckTx calls for the same sender - or a CheckTx racing the
+// checkTxState reset in Commit - can corrupt the speculative nonce and
+// balance, causing valid transactions to be rejected or invalid ones to
+// slip through.
+package locker
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AddrLocker hands out a per-address *sync.Mutex, created lazily and
+// shared by every caller that locks the same address, plus a separate
+// top-level RWMutex callers use to fence access to state shared across
+// addresses (e.g. app.checkTxState itself). The two are independent
+// mutexes on purpose: LockAddr/UnlockAddr only ever touch the map's own
+// bookkeeping mutex, so a caller can hold RLock for an entire read/use of
+// shared state and still call LockAddr without deadlocking.
+type AddrLocker struct {
+	// fence guards access to state shared across addresses. Callers that
+	// read it (validateTx) take RLock for the duration of that read;
+	// Commit takes Lock around the checkTxState swap.
+	fence sync.RWMutex
+
+	// mapMu guards locks, the per-address mutex table, and nothing else.
+	mapMu sync.Mutex
+	locks map[common.Address]*sync.Mutex
+}
+
+// NewAddrLocker returns a ready-to-use AddrLocker.
+func NewAddrLocker() *AddrLocker {
+	return &AddrLocker{locks: make(map[common.Address]*sync.Mutex)}
+}
+
+// LockAddr locks the mutex associated with addr, creating it on first use.
+func (l *AddrLocker) LockAddr(addr common.Address) {
+	l.mapMu.Lock()
+	if l.locks[addr] == nil {
+		l.locks[addr] = new(sync.Mutex)
+	}
+	mu := l.locks[addr]
+	l.mapMu.Unlock()
+	mu.Lock()
+}
+
+// UnlockAddr unlocks the mutex associated with addr. It panics, same as
+// sync.Mutex, if addr isn't currently locked.
+func (l *AddrLocker) UnlockAddr(addr common.Address) {
+	l.mapMu.Lock()
+	mu := l.locks[addr]
+	l.mapMu.Unlock()
+	mu.Unlock()
+}
+
+// RLock fences a read of state shared across addresses - e.g. validateTx
+// capturing app.checkTxState - against a concurrent Lock()'d writer.
+func (l *AddrLocker) RLock() {
+	l.fence.RLock()
+}
+
+// RUnlock releases RLock.
+func (l *AddrLocker) RUnlock() {
+	l.fence.RUnlock()
+}
+
+// Lock fences a write to state shared across addresses - e.g. Commit's
+// checkTxState reassignment - against concurrent RLock readers.
+func (l *AddrLocker) Lock() {
+	l.fence.Lock()
+}
+
+// Unlock releases Lock.
+func (l *AddrLocker) Unlock() {
+	l.fence.Unlock()
+}
@@ -0,0 +1,34 @@
+package locker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BenchmarkAddrLocker_Concurrent measures LockAddr/UnlockAddr throughput
+// under high per-account concurrency, the regime validateTx runs in:
+// many goroutines, each repeatedly locking one of a small pool of
+// addresses, as CheckTx does per sender.
+func BenchmarkAddrLocker_Concurrent(b *testing.B) {
+	const numAddrs = 64
+
+	addrs := make([]common.Address, numAddrs)
+	for i := range addrs {
+		addrs[i] = common.HexToAddress(fmt.Sprintf("0x%040x", i+1))
+	}
+
+	locker := NewAddrLocker()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			addr := addrs[i%numAddrs]
+			locker.LockAddr(addr)
+			locker.UnlockAddr(addr)
+			i++
+		}
+	})
+}
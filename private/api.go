@@ -0,0 +1,39 @@
+package private
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// API exposes eth_sendPrivateTransaction and eth_getPrivateTransaction. It
+// is registered under the "eth" namespace alongside the node's usual
+// transaction pool API.
+type API struct {
+	manager *Manager
+}
+
+// NewAPI returns a private transaction API backed by manager.
+func NewAPI(manager *Manager) *API {
+	return &API{manager: manager}
+}
+
+// SendPrivateTransaction stores payload with the transaction manager on
+// behalf of the participants in privateFor and returns the hash the caller
+// should use as calldata before signing and broadcasting the transaction
+// through the usual eth_sendRawTransaction path, with V set to 37 or 38 to
+// mark it private.
+func (api *API) SendPrivateTransaction(ctx context.Context, payload hexutil.Bytes, privateFor []string) (common.Hash, error) {
+	hash, err := api.manager.Send(payload, privateFor)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.HexToHash(hash), nil
+}
+
+// GetPrivateTransaction returns the decrypted payload for the private
+// transaction carrying calldata hash, if this node participates in it.
+func (api *API) GetPrivateTransaction(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
+	return api.manager.Receive(hash.Hex())
+}
@@ -0,0 +1,51 @@
+// Package private adds Quorum-style private transactions to ethermint: the
+// transaction payload is stored off-chain with a Constellation-like
+// transaction manager, and only participants execute it against their own
+// private state.
+package private
+
+import (
+	"os"
+
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// EnvConfigSocket is the environment variable ethermint reads at startup to
+// locate the transaction manager's IPC socket, mirroring Quorum's
+// PRIVATE_CONFIG variable.
+const EnvConfigSocket = "PRIVATE_CONFIG"
+
+// ConfigFlag lets operators point ethermint at a transaction manager
+// without relying on the process environment; it falls back to
+// PRIVATE_CONFIG when unset.
+var ConfigFlag = cli.StringFlag{
+	Name:   "private.config",
+	Usage:  "IPC socket of the private transaction manager (Constellation-like)",
+	EnvVar: EnvConfigSocket,
+}
+
+// Config describes how to reach the private transaction manager.
+type Config struct {
+	// Socket is the path to the transaction manager's IPC socket.
+	Socket string
+}
+
+// ConfigFromContext resolves Config from the private.config flag (which
+// itself falls back to PRIVATE_CONFIG). ok is false if neither is set,
+// meaning private transactions are disabled for this node.
+func ConfigFromContext(ctx *cli.Context) (cfg Config, ok bool) {
+	if socket := ctx.GlobalString(ConfigFlag.Name); socket != "" {
+		return Config{Socket: socket}, true
+	}
+	return ConfigFromEnv()
+}
+
+// ConfigFromEnv reads Config from the PRIVATE_CONFIG environment variable
+// directly, for callers that don't have a *cli.Context at hand.
+func ConfigFromEnv() (cfg Config, ok bool) {
+	socket := os.Getenv(EnvConfigSocket)
+	if socket == "" {
+		return Config{}, false
+	}
+	return Config{Socket: socket}, true
+}
@@ -0,0 +1,43 @@
+package private
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Manager talks to a transaction manager over IPC, storing and retrieving
+// the payloads of private transactions on behalf of this node.
+type Manager struct {
+	client *rpc.Client
+}
+
+// NewManager dials the transaction manager's IPC socket described by cfg.
+func NewManager(cfg Config) (*Manager, error) {
+	client, err := rpc.Dial(cfg.Socket)
+	if err != nil {
+		return nil, fmt.Errorf("private: dialing transaction manager at %s: %v", cfg.Socket, err)
+	}
+	return &Manager{client: client}, nil
+}
+
+// Send stores payload with the transaction manager, restricted to the
+// participants in privateFor, and returns the hash the caller should carry
+// in place of the real calldata.
+func (m *Manager) Send(payload []byte, privateFor []string) (string, error) {
+	var hash string
+	if err := m.client.Call(&hash, "tm_send", payload, privateFor); err != nil {
+		return "", fmt.Errorf("private: send: %v", err)
+	}
+	return hash, nil
+}
+
+// Receive fetches the payload stored under hash. It returns a nil payload
+// and nil error if this node isn't a participant in that transaction.
+func (m *Manager) Receive(hash string) ([]byte, error) {
+	var payload []byte
+	if err := m.client.Call(&payload, "tm_receive", hash); err != nil {
+		return nil, fmt.Errorf("private: receive %s: %v", hash, err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,87 @@
+package private
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrInvalidSig is returned by Signer.Sender when tx doesn't carry a valid
+// private-tx signature.
+var ErrInvalidSig = errors.New("private: invalid transaction v, r, s values")
+
+// Signer implements ethTypes.Signer for the private-tx marker convention:
+// an unprotected signature whose V is 37 or 38 rather than 27 or 28, with
+// the recovery id encoded as V-37. It exists because ethTypes.HomesteadSigner
+// assumes V-27: handing it a private tx's V of 37/38 computes a recovery id
+// of 10 or 11, which crypto.ValidateSignatureValues always rejects, so
+// Sender() fails on every private transaction.
+type Signer struct{}
+
+// Sender recovers tx's sender from its private-tx signature.
+func (Signer) Sender(tx *ethTypes.Transaction) (common.Address, error) {
+	v, r, s := tx.RawSignatureValues()
+	if v == nil || r == nil || s == nil || v.BitLen() > 8 {
+		return common.Address{}, ErrInvalidSig
+	}
+
+	recoveryID := byte(v.Uint64() - 37)
+	if !crypto.ValidateSignatureValues(recoveryID, r, s, true) {
+		return common.Address{}, ErrInvalidSig
+	}
+
+	rb, sb := r.Bytes(), s.Bytes()
+	sig := make([]byte, 65)
+	copy(sig[32-len(rb):32], rb)
+	copy(sig[64-len(sb):64], sb)
+	sig[64] = recoveryID
+
+	hash := Signer{}.Hash(tx)
+	pub, err := crypto.Ecrecover(hash[:], sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(pub) == 0 || pub[0] != 4 {
+		return common.Address{}, errors.New("private: invalid public key")
+	}
+
+	var addr common.Address
+	copy(addr[:], crypto.Keccak256(pub[1:])[12:])
+	return addr, nil
+}
+
+// SignatureValues returns the r, s, v a signature produces under this
+// scheme, offsetting the recovery id by 37 instead of Homestead's 27 so
+// the resulting tx carries the private-tx marker.
+func (Signer) SignatureValues(tx *ethTypes.Transaction, sig []byte) (r, s, v *big.Int, err error) {
+	if len(sig) != 65 {
+		return nil, nil, nil, fmt.Errorf("private: wrong size for signature: got %d, want 65", len(sig))
+	}
+	r = new(big.Int).SetBytes(sig[:32])
+	s = new(big.Int).SetBytes(sig[32:64])
+	v = new(big.Int).SetUint64(uint64(sig[64]) + 37)
+	return r, s, v, nil
+}
+
+// Hash returns the hash to be signed, identical to HomesteadSigner's since
+// the private-tx marker only changes how V is interpreted, not what's
+// hashed.
+func (Signer) Hash(tx *ethTypes.Transaction) common.Hash {
+	return ethTypes.HomesteadSigner{}.Hash(tx)
+}
+
+// Equal implements ethTypes.Signer.
+func (Signer) Equal(s2 ethTypes.Signer) bool {
+	_, ok := s2.(Signer)
+	return ok
+}
+
+// RecoverSender recovers the sender of a transaction private.IsPrivate has
+// already flagged as private.
+func RecoverSender(tx *ethTypes.Transaction) (common.Address, error) {
+	return ethTypes.Sender(Signer{}, tx)
+}
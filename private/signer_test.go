@@ -0,0 +1,55 @@
+package private
+
+import (
+	"math/big"
+	"testing"
+
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestRecoverSender(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := crypto.PubkeyToAddress(key.PublicKey)
+
+	tx := ethTypes.NewTransaction(0, want, big.NewInt(0), 21000, big.NewInt(1), nil)
+	signed, err := ethTypes.SignTx(tx, Signer{}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, _, _ := signed.RawSignatureValues(); v.Uint64() != 37 && v.Uint64() != 38 {
+		t.Fatalf("expected Signer to produce the private-tx marker V, got %s", v)
+	}
+
+	got, err := RecoverSender(signed)
+	if err != nil {
+		t.Fatalf("RecoverSender: %v", err)
+	}
+	if got != want {
+		t.Fatalf("recovered %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func TestRecoverSenderRejectsHomesteadSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := crypto.PubkeyToAddress(key.PublicKey)
+
+	tx := ethTypes.NewTransaction(0, to, big.NewInt(0), 21000, big.NewInt(1), nil)
+	signed, err := ethTypes.SignTx(tx, ethTypes.HomesteadSigner{}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A Homestead signature's V (27/28) isn't a private-tx marker;
+	// RecoverSender must not silently recover the wrong recovery id for it.
+	if _, err := RecoverSender(signed); err == nil {
+		t.Fatal("expected RecoverSender to reject a V=27/28 signature")
+	}
+}
@@ -0,0 +1,39 @@
+package private
+
+import (
+	"math/big"
+
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// markerV are the raw signature V values that flag a transaction as
+// private, the same convention Quorum uses so wallets don't need a new
+// transaction type to opt in.
+var markerV = map[uint64]bool{37: true, 38: true}
+
+// IsPrivate reports whether tx is a private transaction, identified by its
+// signature's V value (37 or 38).
+//
+// That marker collides with plain EIP-155: v = 35 + 2*chainID + {0,1}
+// also equals 37/38 when chainID is 1, a very common default. chainID
+// must be this node's actual configured chain ID so IsPrivate can tell
+// the two apart; if the marker values are exactly the EIP-155 V values
+// for chainID, every ordinary signed transaction would be misclassified
+// as private, so IsPrivate refuses to mark anything private for that
+// configuration rather than guess wrong.
+func IsPrivate(tx *ethTypes.Transaction, chainID *big.Int) bool {
+	v, _, _ := tx.RawSignatureValues()
+	if v == nil || !markerV[v.Uint64()] {
+		return false
+	}
+
+	if chainID != nil && chainID.Sign() > 0 {
+		eip155V0 := new(big.Int).Add(new(big.Int).Lsh(chainID, 1), big.NewInt(35))
+		eip155V1 := new(big.Int).Add(eip155V0, big.NewInt(1))
+		if v.Cmp(eip155V0) == 0 || v.Cmp(eip155V1) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,42 @@
+package private
+
+import (
+	"math/big"
+	"testing"
+
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestIsPrivate(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := crypto.PubkeyToAddress(key.PublicKey)
+	newTx := func() *ethTypes.Transaction {
+		return ethTypes.NewTransaction(0, to, big.NewInt(0), 21000, big.NewInt(1), nil)
+	}
+	chainID := big.NewInt(1)
+
+	privateTx, err := ethTypes.SignTx(newTx(), Signer{}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// chainID 2's EIP-155 V values are 39/40, so 37/38 is unambiguous there.
+	if !IsPrivate(privateTx, big.NewInt(2)) {
+		t.Fatal("expected a Signer-marked transaction to be flagged private")
+	}
+
+	// An ordinary EIP-155 signature for chainID 1 produces the exact same
+	// V values (37/38) as the private marker. IsPrivate cannot tell them
+	// apart on this chain, so it must not classify either as private
+	// instead of misclassifying every public tx.
+	collidingPublicTx, err := ethTypes.SignTx(newTx(), ethTypes.NewEIP155Signer(chainID), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsPrivate(collidingPublicTx, chainID) {
+		t.Fatal("an EIP-155 chainID=1 signature collides with the private marker and must not be classified as private")
+	}
+}
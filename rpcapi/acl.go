@@ -0,0 +1,31 @@
+package rpcapi
+
+// ACL gates which namespaces a Query call may reach: the default public
+// set, plus whatever an operator named via --rpc.api or
+// --rpc.unsafe-namespaces.
+type ACL struct {
+	allowed map[Namespace]bool
+}
+
+// NewACL builds an ACL from the public default set plus any namespaces in
+// enabled (--rpc.api) or unsafe (--rpc.unsafe-namespaces).
+func NewACL(enabled, unsafe []string) *ACL {
+	allowed := make(map[Namespace]bool, len(defaultAPIs)+len(enabled)+len(unsafe))
+	for _, info := range defaultAPIs {
+		if info.Public {
+			allowed[info.Namespace] = true
+		}
+	}
+	for _, ns := range enabled {
+		allowed[Namespace(ns)] = true
+	}
+	for _, ns := range unsafe {
+		allowed[Namespace(ns)] = true
+	}
+	return &ACL{allowed: allowed}
+}
+
+// Allows reports whether method's namespace may be dispatched.
+func (a *ACL) Allows(method string) bool {
+	return a.allowed[NamespaceOf(method)]
+}
@@ -0,0 +1,40 @@
+package rpcapi
+
+import "testing"
+
+func TestACLAllows(t *testing.T) {
+	acl := NewACL([]string{"personal"}, nil)
+
+	cases := []struct {
+		method string
+		want   bool
+	}{
+		{"eth_getBalance", true},         // public by default
+		{"net_version", true},            // public by default
+		{"personal_unlockAccount", true}, // enabled via --rpc.api
+		{"miner_start", false},           // neither public nor enabled
+		{"debug_traceTransaction", false},
+	}
+
+	for _, c := range cases {
+		if got := acl.Allows(c.method); got != c.want {
+			t.Errorf("Allows(%q) = %v, want %v", c.method, got, c.want)
+		}
+	}
+}
+
+func TestACLUnsafeNamespaces(t *testing.T) {
+	acl := NewACL(nil, []string{"debug"})
+	if !acl.Allows("debug_dumpBlock") {
+		t.Error("expected debug to be allowed once named in unsafe namespaces")
+	}
+}
+
+func TestNamespaceOf(t *testing.T) {
+	if got := NamespaceOf("eth_getBalance"); got != NamespaceEth {
+		t.Errorf("NamespaceOf(eth_getBalance) = %q, want %q", got, NamespaceEth)
+	}
+	if got := NamespaceOf("noUnderscore"); got != Namespace("noUnderscore") {
+		t.Errorf("NamespaceOf(noUnderscore) = %q, want the whole method name", got)
+	}
+}
@@ -0,0 +1,56 @@
+package rpcapi
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+)
+
+// DebugBackend is the subset of the local ethereum backend the debug
+// namespace needs to serve state dumps (debug_dumpBlock) without a
+// round-trip through rpcClient. Block tracing is not served locally; any
+// other debug_* method is rejected by Dispatch.
+type DebugBackend interface {
+	StateAtBlock(hash common.Hash) (*state.StateDB, error)
+}
+
+// DebugAPI implements the debug_* methods EthermintApplication serves
+// itself rather than forwarding to rpcClient, since they need direct
+// access to local state an arbitrary ABCI querier shouldn't be trusted
+// with via the remote node's own RPC endpoint.
+type DebugAPI struct {
+	backend DebugBackend
+}
+
+// NewDebugAPI returns a debug namespace implementation backed by backend.
+func NewDebugAPI(backend DebugBackend) *DebugAPI {
+	return &DebugAPI{backend: backend}
+}
+
+// Dispatch routes a debug_* method to its implementation.
+func (api *DebugAPI) Dispatch(method string, params []interface{}) (interface{}, error) {
+	switch method {
+	case "debug_dumpBlock":
+		return api.dumpBlock(params)
+	default:
+		return nil, fmt.Errorf("rpcapi: unsupported debug method %q", method)
+	}
+}
+
+func (api *DebugAPI) dumpBlock(params []interface{}) (interface{}, error) {
+	if len(params) == 0 {
+		return nil, fmt.Errorf("rpcapi: debug_dumpBlock requires a block hash")
+	}
+	hashStr, ok := params[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("rpcapi: debug_dumpBlock: expected a hex block hash, got %T", params[0])
+	}
+
+	statedb, err := api.backend.StateAtBlock(common.HexToHash(hashStr))
+	if err != nil {
+		return nil, err
+	}
+	dump := statedb.RawDump()
+	return &dump, nil
+}
@@ -0,0 +1,40 @@
+package rpcapi
+
+import (
+	"strings"
+
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var (
+	// APIFlag lists namespaces, beyond the public defaults, a node
+	// exposes over ABCI Query - e.g. "--rpc.api=debug,txpool".
+	APIFlag = cli.StringFlag{
+		Name:  "rpc.api",
+		Usage: "comma-separated list of additional JSON-RPC namespaces to expose via ABCI Query",
+	}
+
+	// UnsafeNamespacesFlag explicitly allows namespaces that can leak keys
+	// or control node behavior (personal, miner). NewACL treats it the same
+	// as rpc.api: a namespace named in either flag is allowed.
+	UnsafeNamespacesFlag = cli.StringFlag{
+		Name:  "rpc.unsafe-namespaces",
+		Usage: "comma-separated list of unsafe namespaces (personal, miner) to expose via ABCI Query",
+	}
+)
+
+// ACLFromContext builds an ACL from the rpc.api and rpc.unsafe-namespaces
+// flags.
+func ACLFromContext(ctx *cli.Context) *ACL {
+	return NewACL(
+		splitCSV(ctx.GlobalString(APIFlag.Name)),
+		splitCSV(ctx.GlobalString(UnsafeNamespacesFlag.Name)),
+	)
+}
+
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
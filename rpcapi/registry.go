@@ -0,0 +1,55 @@
+// Package rpcapi gates which JSON-RPC namespaces EthermintApplication.Query
+// may dispatch to. Without it, Query forwards any {method, params} straight
+// to the underlying geth node's rpcClient, exposing every namespace it has
+// registered - including admin, personal, and miner - to any ABCI querier.
+package rpcapi
+
+import "strings"
+
+// Namespace identifies a JSON-RPC API exposed by the underlying ethereum
+// node, e.g. "eth" in "eth_getBalance".
+type Namespace string
+
+// Namespaces EthermintApplication knows about. This isn't exhaustive of
+// what a geth node can register - it's the set Query is prepared to gate
+// and, for debug, serve itself.
+const (
+	NamespaceEth      Namespace = "eth"
+	NamespaceNet      Namespace = "net"
+	NamespaceWeb3     Namespace = "web3"
+	NamespaceTxPool   Namespace = "txpool"
+	NamespaceDebug    Namespace = "debug"
+	NamespaceMiner    Namespace = "miner"
+	NamespacePersonal Namespace = "personal"
+)
+
+// Info describes one namespace's default gating.
+type Info struct {
+	Namespace Namespace
+	// Public namespaces are reachable via Query without any operator
+	// opt-in. Everything else requires --rpc.api or
+	// --rpc.unsafe-namespaces.
+	Public bool
+}
+
+// defaultAPIs is the built-in registry. miner/personal default to private
+// since they can leak keys or control mining; debug defaults to private
+// since it can dump full state.
+var defaultAPIs = []Info{
+	{Namespace: NamespaceEth, Public: true},
+	{Namespace: NamespaceNet, Public: true},
+	{Namespace: NamespaceWeb3, Public: true},
+	{Namespace: NamespaceTxPool, Public: true},
+	{Namespace: NamespaceDebug, Public: false},
+	{Namespace: NamespaceMiner, Public: false},
+	{Namespace: NamespacePersonal, Public: false},
+}
+
+// NamespaceOf returns the namespace portion of a JSON-RPC method name, e.g.
+// "eth" for "eth_getBalance".
+func NamespaceOf(method string) Namespace {
+	if i := strings.IndexByte(method, '_'); i >= 0 {
+		return Namespace(method[:i])
+	}
+	return Namespace(method)
+}